@@ -0,0 +1,107 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meta
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+
+	"github.com/golang/snappy"
+	. "github.com/milvus-io/milvus/internal/querycoordv2/params"
+)
+
+// HandoffCodec identifies how a handoff event value is encoded on disk (in etcd).
+// It is stored as the first byte of the value so old, uncompressed entries
+// written before this feature existed can still be told apart from new ones.
+type HandoffCodec byte
+
+const (
+	// HandoffCodecRaw means the remaining bytes are the marshaled proto as-is.
+	HandoffCodecRaw HandoffCodec = iota
+	// HandoffCodecGzip means the remaining bytes are gzip-compressed.
+	HandoffCodecGzip
+	// HandoffCodecSnappy means the remaining bytes are snappy-compressed.
+	HandoffCodecSnappy
+)
+
+// handoffCodecHeaderLen is the number of bytes reserved for the codec header.
+const handoffCodecHeaderLen = 1
+
+// EncodeHandoffValue compresses raw (a marshaled querypb.SegmentInfo) according to
+// Params.QueryCoordCfg.HandoffEventCompressionCodec, but only when raw is larger than
+// Params.QueryCoordCfg.HandoffEventCompressionThreshold. The returned bytes are prefixed
+// with a 1-byte codec header so EncodeHandoffValue/DecodeHandoffValue stay in sync across
+// upgrades even if the configured codec changes later.
+func EncodeHandoffValue(raw []byte) ([]byte, error) {
+	codec := HandoffCodec(Params.QueryCoordCfg.HandoffEventCompressionCodec)
+	threshold := Params.QueryCoordCfg.HandoffEventCompressionThreshold
+
+	if codec == HandoffCodecRaw || len(raw) <= threshold {
+		return append([]byte{byte(HandoffCodecRaw)}, raw...), nil
+	}
+
+	switch codec {
+	case HandoffCodecGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(raw); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return append([]byte{byte(HandoffCodecGzip)}, buf.Bytes()...), nil
+	case HandoffCodecSnappy:
+		return append([]byte{byte(HandoffCodecSnappy)}, snappy.Encode(nil, raw)...), nil
+	default:
+		// unknown codec configured, fall back to storing raw bytes
+		return append([]byte{byte(HandoffCodecRaw)}, raw...), nil
+	}
+}
+
+// DecodeHandoffValue reverses EncodeHandoffValue. Values written before this feature
+// existed have no codec header, so any header byte that doesn't map to a known codec is
+// treated as the start of a legacy, uncompressed proto and the value is returned untouched.
+func DecodeHandoffValue(value []byte) ([]byte, error) {
+	if len(value) < handoffCodecHeaderLen {
+		return value, nil
+	}
+
+	switch HandoffCodec(value[0]) {
+	case HandoffCodecRaw:
+		return value[handoffCodecHeaderLen:], nil
+	case HandoffCodecGzip:
+		r, err := gzip.NewReader(bytes.NewReader(value[handoffCodecHeaderLen:]))
+		if err != nil {
+			// not actually gzip, most likely a pre-existing uncompressed entry
+			return value, nil
+		}
+		defer r.Close()
+		return ioutil.ReadAll(r)
+	case HandoffCodecSnappy:
+		decoded, err := snappy.Decode(nil, value[handoffCodecHeaderLen:])
+		if err != nil {
+			// not actually snappy, most likely a pre-existing uncompressed entry
+			return value, nil
+		}
+		return decoded, nil
+	default:
+		// legacy entry written before compression was introduced
+		return value, nil
+	}
+}
@@ -0,0 +1,82 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meta
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/milvus-io/milvus/internal/querycoordv2/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func withCompressionConfig(t *testing.T, codec HandoffCodec, threshold int) {
+	oldCodec := Params.QueryCoordCfg.HandoffEventCompressionCodec
+	oldThreshold := Params.QueryCoordCfg.HandoffEventCompressionThreshold
+	Params.QueryCoordCfg.HandoffEventCompressionCodec = int(codec)
+	Params.QueryCoordCfg.HandoffEventCompressionThreshold = threshold
+	t.Cleanup(func() {
+		Params.QueryCoordCfg.HandoffEventCompressionCodec = oldCodec
+		Params.QueryCoordCfg.HandoffEventCompressionThreshold = oldThreshold
+	})
+}
+
+func TestHandoffValueRoundTrip(t *testing.T) {
+	raw := []byte(strings.Repeat("segment-info-payload", 100))
+
+	for _, codec := range []HandoffCodec{HandoffCodecRaw, HandoffCodecGzip, HandoffCodecSnappy} {
+		withCompressionConfig(t, codec, 0)
+
+		encoded, err := EncodeHandoffValue(raw)
+		assert.NoError(t, err)
+
+		decoded, err := DecodeHandoffValue(encoded)
+		assert.NoError(t, err)
+		assert.True(t, bytes.Equal(raw, decoded))
+	}
+}
+
+func TestEncodeHandoffValueBelowThresholdStaysRaw(t *testing.T) {
+	withCompressionConfig(t, HandoffCodecGzip, 1<<20)
+
+	raw := []byte("small")
+	encoded, err := EncodeHandoffValue(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, byte(HandoffCodecRaw), encoded[0])
+
+	decoded, err := DecodeHandoffValue(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, raw, decoded)
+}
+
+func TestDecodeHandoffValueLegacyFallback(t *testing.T) {
+	// Entries written before this feature existed have no codec header: their first byte is
+	// whatever the proto marshaler produced, which most commonly lands outside the known codec
+	// range and must be returned untouched rather than misinterpreted as a codec tag.
+	legacy := []byte{0xAB, 0x01, 0x02, 0x03}
+
+	decoded, err := DecodeHandoffValue(legacy)
+	assert.NoError(t, err)
+	assert.Equal(t, legacy, decoded)
+}
+
+func TestDecodeHandoffValueEmpty(t *testing.T) {
+	decoded, err := DecodeHandoffValue(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, decoded)
+}
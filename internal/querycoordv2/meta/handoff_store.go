@@ -0,0 +1,87 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meta
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/milvus-io/milvus/internal/kv"
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// handoffEventPrefix is the etcd key prefix segment handoff events are stored under.
+const handoffEventPrefix = "queryCoord-handoff"
+
+// Store is the etcd-backed persistence HandoffObserver uses to save, remove, load, and watch
+// segment handoff events.
+type Store interface {
+	SaveHandoffEvent(info *querypb.SegmentInfo) error
+	RemoveHandoffEvent(info *querypb.SegmentInfo) error
+	LoadHandoffWithRevision() (keys []string, values []string, revision int64, err error)
+	WatchHandoffEvent(revision int64) clientv3.WatchChan
+}
+
+// StoreImpl is the etcd implementation of Store.
+type StoreImpl struct {
+	cli kv.MetaKv
+}
+
+// NewStore returns a Store backed by cli.
+func NewStore(cli kv.MetaKv) *StoreImpl {
+	return &StoreImpl{cli: cli}
+}
+
+func encodeHandoffEventKey(segmentID int64) string {
+	return fmt.Sprintf("%s/%d", handoffEventPrefix, segmentID)
+}
+
+// SaveHandoffEvent marshals info and writes it under its segment's handoff key, compressing the
+// marshaled bytes through EncodeHandoffValue first so a SegmentInfo with many index infos or
+// compaction sources doesn't balloon the etcd value (and the watch stream fanned out to every
+// QueryCoord replica) just because nothing upstream caps SegmentInfo's size.
+func (s *StoreImpl) SaveHandoffEvent(info *querypb.SegmentInfo) error {
+	raw, err := proto.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	value, err := EncodeHandoffValue(raw)
+	if err != nil {
+		return err
+	}
+
+	return s.cli.Save(encodeHandoffEventKey(info.GetSegmentID()), string(value))
+}
+
+// RemoveHandoffEvent deletes info's handoff key once its handoff has fully completed.
+func (s *StoreImpl) RemoveHandoffEvent(info *querypb.SegmentInfo) error {
+	return s.cli.Remove(encodeHandoffEventKey(info.GetSegmentID()))
+}
+
+// LoadHandoffWithRevision loads every handoff event currently in etcd, along with the revision
+// to resume watching from. Values are returned exactly as stored (still codec-prefixed); callers
+// must pass them through DecodeHandoffValue before unmarshaling.
+func (s *StoreImpl) LoadHandoffWithRevision() ([]string, []string, int64, error) {
+	return s.cli.LoadWithRevision(handoffEventPrefix)
+}
+
+// WatchHandoffEvent resumes watching handoff events from revision.
+func (s *StoreImpl) WatchHandoffEvent(revision int64) clientv3.WatchChan {
+	return s.cli.WatchWithRevision(handoffEventPrefix, revision)
+}
@@ -0,0 +1,82 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observers
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestHandoffObserver() *HandoffObserver {
+	return &HandoffObserver{leaderChanged: make(chan bool, 1)}
+}
+
+func TestRunOrForwardBuffersUntilLeader(t *testing.T) {
+	ob := newTestHandoffObserver()
+
+	var ran int32
+	ob.runOrForward(func() { atomic.AddInt32(&ran, 1) })
+	assert.Equal(t, int32(0), atomic.LoadInt32(&ran), "op should be buffered while not leader")
+
+	pending := ob.setLeader(true)
+	assert.Len(t, pending, 1)
+	for _, op := range pending {
+		op()
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&ran))
+
+	ob.runOrForward(func() { atomic.AddInt32(&ran, 1) })
+	assert.Equal(t, int32(2), atomic.LoadInt32(&ran), "op should run immediately once leader")
+}
+
+// TestRunOrForwardNeverLosesOpsAcrossLeaderTransition guards the race fixed in runOrForward:
+// an op racing a concurrent setLeader(true) must either run directly (it observed isLeader
+// already true) or land in pendingOps to be replayed, never neither.
+func TestRunOrForwardNeverLosesOpsAcrossLeaderTransition(t *testing.T) {
+	const n = 200
+
+	for i := 0; i < 20; i++ {
+		ob := newTestHandoffObserver()
+		var ranCount int32
+		var wg sync.WaitGroup
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ob.setLeader(true)
+		}()
+
+		for j := 0; j < n; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ob.runOrForward(func() { atomic.AddInt32(&ranCount, 1) })
+			}()
+		}
+		wg.Wait()
+
+		// Replay whatever didn't run directly; nothing should remain unaccounted for.
+		for _, op := range ob.setLeader(true) {
+			op()
+		}
+
+		assert.Equal(t, int32(n), atomic.LoadInt32(&ranCount))
+	}
+}
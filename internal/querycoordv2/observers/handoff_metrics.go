@@ -0,0 +1,117 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observers
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// formatCollectionID is the shared label formatter for all handoff metrics, kept in one place
+// so every metric labels collections the same way.
+func formatCollectionID(collectionID int64) string {
+	return strconv.FormatInt(collectionID, 10)
+}
+
+// Handoff lifecycle metrics. Cardinality is bounded by labelling on collection ID only, never
+// on segment ID, so it stays flat regardless of how many segments a collection churns through.
+var (
+	handoffEventsReceivedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "milvus",
+			Subsystem: "queryCoord",
+			Name:      "handoff_events_received_total",
+			Help:      "Number of handoff events observed from etcd, by collection",
+		},
+		[]string{"collection_id"},
+	)
+
+	handoffEventsTriggeredTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "milvus",
+			Subsystem: "queryCoord",
+			Name:      "handoff_events_triggered_total",
+			Help:      "Number of handoff events actually triggered (registered to the target manager), by collection",
+		},
+		[]string{"collection_id"},
+	)
+
+	handoffEventsCleanedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "milvus",
+			Subsystem: "queryCoord",
+			Name:      "handoff_events_cleaned_total",
+			Help:      "Number of handoff events removed from etcd after handoff completed, by collection",
+		},
+		[]string{"collection_id"},
+	)
+
+	handoffCleanRetriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "milvus",
+			Subsystem: "queryCoord",
+			Name:      "handoff_clean_retries_total",
+			Help:      "Number of retries spent removing a handoff event from etcd, by collection",
+		},
+		[]string{"collection_id"},
+	)
+
+	handoffPendingEvents = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "milvus",
+			Subsystem: "queryCoord",
+			Name:      "handoff_pending_events",
+			Help:      "Number of handoff events currently held in HandoffObserver.handoffEvents, by collection",
+		},
+		[]string{"collection_id"},
+	)
+
+	handoffLatencySeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "milvus",
+			Subsystem: "queryCoord",
+			Name:      "handoff_latency_seconds",
+			Help:      "Wall time from a handoff event being received to it being cleaned up, by collection",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+		},
+		[]string{"collection_id"},
+	)
+
+	// handoffSubscriberDropsTotal has no labels: it counts Subscribe() sends dropped because a
+	// subscriber's channel was full, which is a subscriber-side problem, not a per-collection one.
+	handoffSubscriberDropsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "milvus",
+			Subsystem: "queryCoord",
+			Name:      "handoff_subscriber_drops_total",
+			Help:      "Number of HandoffObserver.Subscribe() lifecycle records dropped because a subscriber was too slow",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		handoffEventsReceivedTotal,
+		handoffEventsTriggeredTotal,
+		handoffEventsCleanedTotal,
+		handoffCleanRetriesTotal,
+		handoffPendingEvents,
+		handoffLatencySeconds,
+		handoffSubscriberDropsTotal,
+	)
+}
@@ -18,6 +18,7 @@ package observers
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -32,9 +33,25 @@ import (
 	"github.com/milvus-io/milvus/internal/util/typeutil"
 	"github.com/samber/lo"
 	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 )
 
+// handoffLeaderPath is the etcd key prefix HandoffObserver replicas campaign on to elect the
+// single instance allowed to trigger/release/clean handoff events.
+const handoffLeaderPath = "queryCoord-handoff-leader"
+
+// handoffWorkerCount is how many goroutines concurrently drain handoffWorkQueue and register
+// segments with the target manager, independent of the single schedule() goroutine that owns
+// the etcd watch channel.
+const handoffWorkerCount = 4
+
+// handoffWorkQueueSize bounds how many segments can be queued for rate-limited registration
+// before handoff() starts blocking its caller, trading memory for not dropping work.
+const handoffWorkQueueSize = 1024
+
 type CollectionHandoffStatus int32
 type HandoffEventStatus int32
 
@@ -47,6 +64,11 @@ const (
 
 const (
 	HandoffEventStatusReceived HandoffEventStatus = iota + 1
+	// HandoffEventStatusTriggering means tryHandoff decided to hand the segment off and queued
+	// it on handoffWorkQueue, but doHandoff hasn't registered it with the target manager yet.
+	// tryRelease must not run until the status advances past this to HandoffEventStatusTriggered,
+	// or it could see the segment missing from target and release its compactFrom sources early.
+	HandoffEventStatusTriggering
 	HandoffEventStatusTriggered
 )
 
@@ -59,6 +81,8 @@ type queue []int64
 
 type HandoffObserver struct {
 	store    meta.Store
+	etcdCli  *clientv3.Client
+	nodeID   int64
 	c        chan struct{}
 	wg       sync.WaitGroup
 	meta     *meta.Meta
@@ -72,12 +96,45 @@ type HandoffObserver struct {
 	// partition id -> queue
 	handoffSubmitOrders map[int64]queue
 
+	// leader election, see campaign(). leaderStateLock guards isLeader, pendingOps, and
+	// leaderCancel together: isLeader must flip and pendingOps must drain as one atomic step, or
+	// a runOrForward call straddling the flip could append an op that's never replayed (see
+	// runOrForward), and leaderCancel is written/read from different goroutines (runAsLeader and
+	// Stop).
+	leaderStateLock sync.Mutex
+	isLeader        bool
+	leaderChanged   chan bool
+	leaderCancel    context.CancelFunc
+
+	// operations buffered while this node is not the leader, replayed once this node is
+	// elected; they are never sent to another replica. Guarded by leaderStateLock, see above.
+	pendingOps []func()
+
+	// handoffRateLimiter caps how often doHandoff actually registers a segment with the target
+	// manager, see doHandoff. The limiter is only ever waited on by runHandoffWorker, never
+	// inline in schedule(), so a burst of queued work rate-limits without stalling watch-channel
+	// consumption; see handoffWorkQueue.
+	handoffRateLimiter *rate.Limiter
+	// handoffWorkQueue buffers segments queued for rate-limited registration; see handoff() and
+	// runHandoffWorker().
+	handoffWorkQueue   chan *querypb.SegmentInfo
+	hotCollectionsLock sync.RWMutex
+	hotCollections     map[int64]struct{}
+
+	// observability, see handoff_metrics.go and handoff_subscribe.go
+	subscribersLock sync.RWMutex
+	subscribers     map[chan HandoffLifecycleRecord]struct{}
+	receivedAtLock  sync.Mutex
+	receivedAt      map[int64]time.Time
+
 	stopOnce sync.Once
 }
 
-func NewHandoffObserver(store meta.Store, meta *meta.Meta, dist *meta.DistributionManager, target *meta.TargetManager) *HandoffObserver {
+func NewHandoffObserver(store meta.Store, meta *meta.Meta, dist *meta.DistributionManager, target *meta.TargetManager, etcdCli *clientv3.Client, nodeID int64) *HandoffObserver {
 	return &HandoffObserver{
 		store:               store,
+		etcdCli:             etcdCli,
+		nodeID:              nodeID,
 		c:                   make(chan struct{}),
 		meta:                meta,
 		dist:                dist,
@@ -85,34 +142,112 @@ func NewHandoffObserver(store meta.Store, meta *meta.Meta, dist *meta.Distributi
 		collectionStatus:    map[int64]CollectionHandoffStatus{},
 		handoffEvents:       map[int64]*HandoffEvent{},
 		handoffSubmitOrders: map[int64]queue{},
+		leaderChanged:       make(chan bool, 1),
+		handoffRateLimiter:  rate.NewLimiter(rate.Limit(Params.QueryCoordCfg.HandoffTaskRateLimit), int(Params.QueryCoordCfg.HandoffTaskRateLimit)+1),
+		handoffWorkQueue:    make(chan *querypb.SegmentInfo, handoffWorkQueueSize),
+		hotCollections:      map[int64]struct{}{},
+		receivedAt:          map[int64]time.Time{},
+		subscribers:         map[chan HandoffLifecycleRecord]struct{}{},
 	}
 }
 
-func (ob *HandoffObserver) Register(collectionIDs ...int64) {
-	ob.handoffEventLock.Lock()
-	defer ob.handoffEventLock.Unlock()
+// IsLeader returns whether this HandoffObserver instance currently holds the handoff
+// leadership and is the one actually triggering/releasing/cleaning handoff events.
+func (ob *HandoffObserver) IsLeader() bool {
+	ob.leaderStateLock.Lock()
+	defer ob.leaderStateLock.Unlock()
+	return ob.isLeader
+}
+
+// LeaderChanged notifies of leadership transitions: true when this node becomes leader,
+// false when it steps down. It is buffered so a slow reader never blocks the election loop.
+func (ob *HandoffObserver) LeaderChanged() <-chan bool {
+	return ob.leaderChanged
+}
 
-	for _, collectionID := range collectionIDs {
-		ob.collectionStatus[collectionID] = CollectionHandoffStatusRegistered
+func (ob *HandoffObserver) notifyLeaderChanged(isLeader bool) {
+	select {
+	case ob.leaderChanged <- isLeader:
+	default:
+		// drop the stale notification and replace it with the latest state
+		select {
+		case <-ob.leaderChanged:
+		default:
+		}
+		ob.leaderChanged <- isLeader
 	}
 }
 
-func (ob *HandoffObserver) Unregister(ctx context.Context, collectionIDs ...int64) {
-	ob.handoffEventLock.Lock()
-	defer ob.handoffEventLock.Unlock()
+// runOrForward runs op immediately if this node is the leader, otherwise buffers it to be
+// replayed once leadership is (re)acquired, so followers never race the leader on state. The
+// leadership check and the buffer append happen under the same lock as the leader transition in
+// setLeader, so a leadership change can never land in the gap between the two: either op sees
+// isLeader already true and runs directly, or it's appended before setLeader(true) can observe
+// and drain pendingOps.
+func (ob *HandoffObserver) runOrForward(op func()) {
+	ob.leaderStateLock.Lock()
+	isLeader := ob.isLeader
+	if !isLeader {
+		ob.pendingOps = append(ob.pendingOps, op)
+	}
+	ob.leaderStateLock.Unlock()
 
-	for _, collectionID := range collectionIDs {
-		delete(ob.collectionStatus, collectionID)
+	if isLeader {
+		op()
 	}
 }
 
-func (ob *HandoffObserver) StartHandoff(collectionIDs ...int64) {
-	ob.handoffEventLock.Lock()
-	defer ob.handoffEventLock.Unlock()
+// setLeader flips isLeader and, when becoming leader, atomically drains pendingOps so the two
+// always move together; see runOrForward. The drained ops are returned for the caller to run
+// outside the lock.
+func (ob *HandoffObserver) setLeader(isLeader bool) []func() {
+	ob.leaderStateLock.Lock()
+	defer ob.leaderStateLock.Unlock()
 
-	for _, collectionID := range collectionIDs {
-		ob.collectionStatus[collectionID] = CollectionHandoffStatusStarted
+	ob.isLeader = isLeader
+	if !isLeader {
+		return nil
 	}
+
+	ops := ob.pendingOps
+	ob.pendingOps = nil
+	return ops
+}
+
+// Register marks collectionIDs as ready to receive handoff events. On a follower node this
+// is buffered locally and replayed only if this node is later elected; it is not sent to the
+// current leader. Only the leader's collectionStatus drives tryHandoff.
+func (ob *HandoffObserver) Register(collectionIDs ...int64) {
+	ob.runOrForward(func() {
+		ob.handoffEventLock.Lock()
+		defer ob.handoffEventLock.Unlock()
+
+		for _, collectionID := range collectionIDs {
+			ob.collectionStatus[collectionID] = CollectionHandoffStatusRegistered
+		}
+	})
+}
+
+func (ob *HandoffObserver) Unregister(ctx context.Context, collectionIDs ...int64) {
+	ob.runOrForward(func() {
+		ob.handoffEventLock.Lock()
+		defer ob.handoffEventLock.Unlock()
+
+		for _, collectionID := range collectionIDs {
+			delete(ob.collectionStatus, collectionID)
+		}
+	})
+}
+
+func (ob *HandoffObserver) StartHandoff(collectionIDs ...int64) {
+	ob.runOrForward(func() {
+		ob.handoffEventLock.Lock()
+		defer ob.handoffEventLock.Unlock()
+
+		for _, collectionID := range collectionIDs {
+			ob.collectionStatus[collectionID] = CollectionHandoffStatusStarted
+		}
+	})
 }
 
 func (ob *HandoffObserver) consumeOutdatedHandoffEvent(ctx context.Context) error {
@@ -125,9 +260,14 @@ func (ob *HandoffObserver) consumeOutdatedHandoffEvent(ctx context.Context) erro
 	ob.revision = revision
 
 	for _, value := range handoffReqValues {
-		segmentInfo := &querypb.SegmentInfo{}
-		err := proto.Unmarshal([]byte(value), segmentInfo)
+		raw, err := meta.DecodeHandoffValue([]byte(value))
 		if err != nil {
+			log.Error("reloadFromKV: decompress handoff value failed", zap.Error(err))
+			return err
+		}
+
+		segmentInfo := &querypb.SegmentInfo{}
+		if err := proto.Unmarshal(raw, segmentInfo); err != nil {
 			log.Error("reloadFromKV: unmarshal failed", zap.Error(err))
 			return err
 		}
@@ -137,16 +277,12 @@ func (ob *HandoffObserver) consumeOutdatedHandoffEvent(ctx context.Context) erro
 	return nil
 }
 
+// Start campaigns for the handoff leadership and keeps re-campaigning across session loss
+// for the lifetime of ctx. Only the elected leader runs schedule/tryHandoff/tryRelease/
+// tryClean; other replicas run a read-only watch to keep handoffEvents warm for fast failover.
 func (ob *HandoffObserver) Start(ctx context.Context) error {
-	log.Info("Start reload handoff event from etcd")
-	if err := ob.consumeOutdatedHandoffEvent(ctx); err != nil {
-		log.Error("handoff observer reload from kv failed", zap.Error(err))
-		return err
-	}
-	log.Info("Finish reload handoff event from etcd")
-
 	ob.wg.Add(1)
-	go ob.schedule(ctx)
+	go ob.campaign(ctx)
 
 	return nil
 }
@@ -154,12 +290,148 @@ func (ob *HandoffObserver) Start(ctx context.Context) error {
 func (ob *HandoffObserver) Stop() {
 	ob.stopOnce.Do(func() {
 		close(ob.c)
+
+		ob.leaderStateLock.Lock()
+		leaderCancel := ob.leaderCancel
+		ob.leaderStateLock.Unlock()
+		if leaderCancel != nil {
+			leaderCancel()
+		}
+
 		ob.wg.Wait()
 	})
 }
 
-func (ob *HandoffObserver) schedule(ctx context.Context) {
+// campaign repeatedly contends for handoffLeaderPath. It never returns while ctx is alive,
+// so a lost session (etcd partition, process pause past the lease TTL, ...) just triggers a
+// new campaign rather than leaving the replica set leaderless.
+func (ob *HandoffObserver) campaign(ctx context.Context) {
 	defer ob.wg.Done()
+
+	// election.Campaign below has no cancellation hook besides ctx, so Stop() closing ob.c must
+	// cancel this derived ctx too, or a replica still contending (not yet leader, leaderCancel
+	// still nil) would block Stop()'s wg.Wait() forever.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-ob.c:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ob.c:
+			return
+		default:
+		}
+
+		session, err := concurrency.NewSession(ob.etcdCli, concurrency.WithTTL(int(Params.QueryCoordCfg.HandoffLeaseTTL.Seconds())))
+		if err != nil {
+			log.Warn("HandoffObserver: failed to create election session, retrying", zap.Error(err))
+			select {
+			case <-time.After(time.Second):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+
+		election := concurrency.NewElection(session, handoffLeaderPath)
+		log.Info("HandoffObserver: campaigning for handoff leadership", zap.Int64("nodeID", ob.nodeID))
+
+		followerCtx, stopFollowerWatch := context.WithCancel(ctx)
+		followerDone := make(chan struct{})
+		go func() {
+			defer close(followerDone)
+			ob.followerWatch(followerCtx)
+		}()
+
+		err = election.Campaign(ctx, fmt.Sprintf("%d", ob.nodeID))
+		stopFollowerWatch()
+		<-followerDone
+		if err != nil {
+			session.Close()
+			if ctx.Err() != nil {
+				return
+			}
+			log.Warn("HandoffObserver: campaign failed, retrying", zap.Error(err))
+			continue
+		}
+
+		ob.runAsLeader(ctx, session)
+		session.Close()
+	}
+}
+
+// runAsLeader blocks until the leadership session is lost (or ctx is done), running the
+// handoff schedule loop as the sole leader in the meantime.
+func (ob *HandoffObserver) runAsLeader(ctx context.Context, session *concurrency.Session) {
+	log.Info("HandoffObserver: elected handoff leader", zap.Int64("nodeID", ob.nodeID))
+
+	// re-run consumeOutdatedHandoffEvent to reset the watch revision. It removes every handoff
+	// event already stored in etcd, including ones put while this node was not leader, without
+	// handing them off; only events the follower watch recorded stay in handoffEvents.
+	if err := ob.consumeOutdatedHandoffEvent(ctx); err != nil {
+		log.Error("HandoffObserver: reload handoff events on election failed", zap.Error(err))
+		return
+	}
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	ob.leaderStateLock.Lock()
+	ob.leaderCancel = cancel
+	ob.leaderStateLock.Unlock()
+	defer cancel()
+
+	pendingOps := ob.setLeader(true)
+	ob.notifyLeaderChanged(true)
+	for _, op := range pendingOps {
+		op()
+	}
+	defer func() {
+		ob.setLeader(false)
+		ob.notifyLeaderChanged(false)
+
+		// drop leader state: handoffEvents is refilled only from new etcd PUTs seen by
+		// followerWatch, and collectionStatus only by later Register/StartHandoff calls
+		ob.handoffEventLock.Lock()
+		ob.collectionStatus = map[int64]CollectionHandoffStatus{}
+		ob.handoffEvents = map[int64]*HandoffEvent{}
+		ob.handoffSubmitOrders = map[int64]queue{}
+		ob.handoffEventLock.Unlock()
+	}()
+
+	scheduleDone := make(chan struct{})
+	go func() {
+		defer close(scheduleDone)
+		ob.schedule(leaderCtx)
+	}()
+
+	var workersWg sync.WaitGroup
+	for i := 0; i < handoffWorkerCount; i++ {
+		workersWg.Add(1)
+		go func() {
+			defer workersWg.Done()
+			ob.runHandoffWorker(leaderCtx)
+		}()
+	}
+
+	select {
+	case <-session.Done():
+		log.Warn("HandoffObserver: lost handoff leadership session", zap.Int64("nodeID", ob.nodeID))
+	case <-ctx.Done():
+	case <-ob.c:
+	}
+	cancel()
+	<-scheduleDone
+	workersWg.Wait()
+}
+
+func (ob *HandoffObserver) schedule(ctx context.Context) {
 	log.Info("start watch Segment handoff loop")
 	ticker := time.NewTicker(Params.QueryCoordCfg.CheckHandoffInterval)
 	log.Info("handoff interval", zap.String("interval", Params.QueryCoordCfg.CheckHandoffInterval.String()))
@@ -185,15 +457,28 @@ func (ob *HandoffObserver) schedule(ctx context.Context) {
 			}
 
 			for _, event := range resp.Events {
-				segmentInfo := &querypb.SegmentInfo{}
-				err := proto.Unmarshal(event.Kv.Value, segmentInfo)
+				raw, err := meta.DecodeHandoffValue(event.Kv.Value)
 				if err != nil {
+					log.Error("failed to decompress handoff event", zap.Error(err))
+					continue
+				}
+
+				segmentInfo := &querypb.SegmentInfo{}
+				if err := proto.Unmarshal(raw, segmentInfo); err != nil {
 					log.Error("failed to deserialize handoff event", zap.Error(err))
 					continue
 				}
 
 				switch event.Type {
 				case mvccpb.PUT:
+					handoffEventsReceivedTotal.WithLabelValues(formatCollectionID(segmentInfo.GetCollectionID())).Inc()
+					ob.markReceived(segmentInfo.GetSegmentID())
+					ob.emit(HandoffLifecycleRecord{
+						SegmentID:    segmentInfo.GetSegmentID(),
+						CollectionID: segmentInfo.GetCollectionID(),
+						Phase:        HandoffPhaseReceived,
+						Timestamp:    time.Now(),
+					})
 					ob.tryHandoff(ctx, segmentInfo)
 				default:
 					log.Warn("HandoffObserver: receive event",
@@ -204,7 +489,20 @@ func (ob *HandoffObserver) schedule(ctx context.Context) {
 			}
 
 		case <-ticker.C:
+			q := newHandoffQueue()
 			for _, event := range ob.handoffEvents {
+				q.push(event, ob.isPriorityEvent(event.Segment))
+			}
+
+			for _, event := range q.drain() {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ob.c:
+					return
+				default:
+				}
+
 				switch event.Status {
 				case HandoffEventStatusReceived:
 					ob.tryHandoff(ctx, event.Segment)
@@ -218,6 +516,59 @@ func (ob *HandoffObserver) schedule(ctx context.Context) {
 	}
 }
 
+// followerWatch keeps handoffEvents warm on a non-leader replica by watching the same etcd
+// prefix read-only: it records received events so they are already in handoffEvents when this
+// replica is elected, but it never calls tryHandoff/tryRelease/
+// tryClean, which remain the leader's exclusive responsibility.
+func (ob *HandoffObserver) followerWatch(ctx context.Context) {
+	_, _, revision, err := ob.store.LoadHandoffWithRevision()
+	if err != nil {
+		log.Warn("HandoffObserver: follower failed to load handoff revision", zap.Error(err))
+		return
+	}
+
+	watchChan := ob.store.WatchHandoffEvent(revision + 1)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case resp, ok := <-watchChan:
+			if !ok {
+				return
+			}
+
+			for _, event := range resp.Events {
+				if event.Type != mvccpb.PUT {
+					continue
+				}
+
+				raw, err := meta.DecodeHandoffValue(event.Kv.Value)
+				if err != nil {
+					continue
+				}
+
+				segmentInfo := &querypb.SegmentInfo{}
+				if err := proto.Unmarshal(raw, segmentInfo); err != nil {
+					continue
+				}
+
+				ob.handoffEventLock.Lock()
+				if _, ok := ob.handoffEvents[segmentInfo.GetSegmentID()]; !ok {
+					ob.handoffEvents[segmentInfo.GetSegmentID()] = &HandoffEvent{
+						Segment: segmentInfo,
+						Status:  HandoffEventStatusReceived,
+					}
+				}
+				ob.handoffEventLock.Unlock()
+			}
+		}
+	}
+}
+
+// tryHandoff does not recompute handoffPendingEvents itself: the ticker branch in schedule()
+// calls it once per drained event, which would make recomputing the gauge here (O(len(
+// handoffEvents))) O(n^2) per tick for a large backlog. tryClean runs once per tick after all of
+// a tick's tryHandoff/tryRelease calls and refreshes the gauge there instead.
 func (ob *HandoffObserver) tryHandoff(ctx context.Context, segment *querypb.SegmentInfo) {
 	ob.handoffEventLock.Lock()
 	defer ob.handoffEventLock.Unlock()
@@ -256,14 +607,29 @@ func (ob *HandoffObserver) tryHandoff(ctx context.Context, segment *querypb.Segm
 			return
 		}
 
+		// Fakes skip doHandoff entirely (they're never registered with the target manager), so
+		// they go straight to Triggered. Real segments start Triggering until doHandoff confirms
+		// registration, so tryRelease can't mistake "not queued yet" for "safe to release".
+		eventStatus := HandoffEventStatusTriggered
+		if !segment.GetIsFake() {
+			eventStatus = HandoffEventStatusTriggering
+		}
 		ob.handoffEvents[segment.GetSegmentID()] = &HandoffEvent{
 			Segment: segment,
-			Status:  HandoffEventStatusTriggered,
+			Status:  eventStatus,
 		}
 
+		handoffEventsTriggeredTotal.WithLabelValues(formatCollectionID(segment.GetCollectionID())).Inc()
+		ob.emit(HandoffLifecycleRecord{
+			SegmentID:    segment.GetSegmentID(),
+			CollectionID: segment.GetCollectionID(),
+			Phase:        HandoffPhaseTriggered,
+			Timestamp:    time.Now(),
+		})
+
 		if !segment.GetIsFake() {
 			log.Info("start to do handoff...")
-			ob.handoff(segment)
+			ob.handoff(ctx, segment)
 		}
 	} else {
 		// ignore handoff task
@@ -272,7 +638,43 @@ func (ob *HandoffObserver) tryHandoff(ctx context.Context, segment *querypb.Segm
 	}
 }
 
-func (ob *HandoffObserver) handoff(segment *querypb.SegmentInfo) {
+// handoff queues segment for rate-limited registration with the target manager, performed by
+// runHandoffWorker. Queuing here (instead of waiting on handoffRateLimiter inline) keeps a
+// compaction storm's burst of handoff events from stalling this goroutine, which is also the
+// one draining the etcd watch channel in schedule().
+func (ob *HandoffObserver) handoff(ctx context.Context, segment *querypb.SegmentInfo) {
+	select {
+	case ob.handoffWorkQueue <- segment:
+	case <-ctx.Done():
+		log.Warn("HandoffObserver: context done before handoff could be queued",
+			zap.Int64("segmentID", segment.GetSegmentID()))
+	}
+}
+
+// runHandoffWorker drains handoffWorkQueue until ctx is done, rate-limiting and performing the
+// actual target-manager registration off the schedule() goroutine. Several of these run
+// concurrently (see handoffWorkerCount) so a single slow registration doesn't also stall the
+// rest of the queue.
+func (ob *HandoffObserver) runHandoffWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case segment := <-ob.handoffWorkQueue:
+			ob.doHandoff(ctx, segment)
+		}
+	}
+}
+
+// doHandoff registers segment with the target manager. It blocks on handoffRateLimiter first,
+// so a compaction storm producing a burst of handoff events can't hammer the target manager.
+func (ob *HandoffObserver) doHandoff(ctx context.Context, segment *querypb.SegmentInfo) {
+	if err := ob.handoffRateLimiter.Wait(ctx); err != nil {
+		log.Warn("HandoffObserver: rate limiter wait canceled before handoff",
+			zap.Int64("segmentID", segment.GetSegmentID()), zap.Error(err))
+		return
+	}
+
 	targets := ob.target.GetSegmentsByCollection(segment.GetCollectionID(), segment.GetPartitionID())
 	// when handoff event load a Segment, it sobuld remove all recursive handoff compact from
 	uniqueSet := typeutil.NewUniqueSet()
@@ -293,6 +695,15 @@ func (ob *HandoffObserver) handoff(segment *querypb.SegmentInfo) {
 
 	log.Info("HandoffObserver: handoff Segment, register to target")
 	ob.target.HandoffSegment(segmentInfo, segmentInfo.CompactionFrom...)
+
+	// advance Triggering to Triggered now that segment is actually on target, so tryRelease
+	// (which only runs against Triggered events) can't observe it missing and release its
+	// compactFrom sources before this registration has taken effect.
+	ob.handoffEventLock.Lock()
+	if event, ok := ob.handoffEvents[segment.GetSegmentID()]; ok {
+		event.Status = HandoffEventStatusTriggered
+	}
+	ob.handoffEventLock.Unlock()
 }
 
 func (ob *HandoffObserver) isSegmentReleased(id int64) bool {
@@ -371,12 +782,20 @@ func (ob *HandoffObserver) tryRelease(ctx context.Context, event *HandoffEvent)
 				ob.target.RemoveSegment(toRelease)
 			}
 		}
+
+		ob.emit(HandoffLifecycleRecord{
+			SegmentID:    segment.GetSegmentID(),
+			CollectionID: segment.GetCollectionID(),
+			Phase:        HandoffPhaseReleased,
+			Timestamp:    time.Now(),
+		})
 	}
 }
 
 func (ob *HandoffObserver) tryClean(ctx context.Context) {
 	ob.handoffEventLock.Lock()
 	defer ob.handoffEventLock.Unlock()
+	defer ob.updatePendingGaugeLocked()
 
 	for partitionID, partitionSubmitOrder := range ob.handoffSubmitOrders {
 		pos := 0
@@ -414,15 +833,34 @@ func (ob *HandoffObserver) cleanEvent(ctx context.Context, segmentInfo *querypb.
 		zap.Int64("segmentID", segmentInfo.SegmentID),
 	)
 
+	collectionLabel := formatCollectionID(segmentInfo.GetCollectionID())
+
 	// add retry logic
+	attempt := 0
 	err := retry.Do(ctx, func() error {
+		if attempt > 0 {
+			handoffCleanRetriesTotal.WithLabelValues(collectionLabel).Inc()
+		}
+		attempt++
 		return ob.store.RemoveHandoffEvent(segmentInfo)
 	}, retry.Attempts(5))
 
 	if err != nil {
 		log.Warn("failed to clean handoff event from etcd", zap.Error(err))
+		return err
 	}
-	return err
+
+	handoffEventsCleanedTotal.WithLabelValues(collectionLabel).Inc()
+	ob.emit(HandoffLifecycleRecord{
+		SegmentID:    segmentInfo.GetSegmentID(),
+		CollectionID: segmentInfo.GetCollectionID(),
+		Phase:        HandoffPhaseCleaned,
+		Timestamp:    time.Now(),
+	})
+	if receivedAt, ok := ob.takeReceivedAt(segmentInfo.GetSegmentID()); ok {
+		handoffLatencySeconds.WithLabelValues(collectionLabel).Observe(time.Since(receivedAt).Seconds())
+	}
+	return nil
 }
 
 func (ob *HandoffObserver) isSegmentExistOnTarget(segmentInfo *querypb.SegmentInfo) bool {
@@ -439,4 +877,4 @@ func (ob *HandoffObserver) isAllCompactFromReleased(segmentInfo *querypb.Segment
 		}
 	}
 	return true
-}
\ No newline at end of file
+}
@@ -0,0 +1,121 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observers
+
+import (
+	"sync"
+
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	. "github.com/milvus-io/milvus/internal/querycoordv2/params"
+)
+
+// handoffQueue fans the events due for a schedule() tick out into one FIFO per collection and
+// drains them round-robin, so a collection with many outstanding events (e.g. a compaction
+// storm on one large collection) can't starve the others. Within a collection's FIFO,
+// priority events (see HandoffObserver.isPriorityEvent) are moved to the front instead of
+// appended, so fake segments, small segments, and hot collections get handed off first.
+// handoffSubmitOrders/tryClean are untouched by this reordering: they only govern cleanup
+// order, not the order tryHandoff/tryRelease are attempted in.
+type handoffQueue struct {
+	mu     sync.Mutex
+	order  []int64 // round-robin cursor over collections with pending events
+	queues map[int64][]*HandoffEvent
+}
+
+func newHandoffQueue() *handoffQueue {
+	return &handoffQueue{queues: map[int64][]*HandoffEvent{}}
+}
+
+func (q *handoffQueue) push(event *HandoffEvent, priority bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	collectionID := event.Segment.GetCollectionID()
+	if _, ok := q.queues[collectionID]; !ok {
+		q.order = append(q.order, collectionID)
+	}
+	if priority {
+		q.queues[collectionID] = append([]*HandoffEvent{event}, q.queues[collectionID]...)
+	} else {
+		q.queues[collectionID] = append(q.queues[collectionID], event)
+	}
+}
+
+// drain empties the queue and returns every pending event in collection round-robin order.
+func (q *handoffQueue) drain() []*HandoffEvent {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var drained []*HandoffEvent
+	for len(q.order) > 0 {
+		collectionID := q.order[0]
+		events := q.queues[collectionID]
+		if len(events) == 0 {
+			q.order = q.order[1:]
+			delete(q.queues, collectionID)
+			continue
+		}
+
+		drained = append(drained, events[0])
+		if len(events) == 1 {
+			q.order = q.order[1:]
+			delete(q.queues, collectionID)
+		} else {
+			q.queues[collectionID] = events[1:]
+			q.order = append(q.order[1:], collectionID)
+		}
+	}
+	return drained
+}
+
+// MarkHot marks collectionIDs as high priority: their handoff events are moved to the front
+// of their per-collection queue instead of appended.
+func (ob *HandoffObserver) MarkHot(collectionIDs ...int64) {
+	ob.hotCollectionsLock.Lock()
+	defer ob.hotCollectionsLock.Unlock()
+
+	for _, collectionID := range collectionIDs {
+		ob.hotCollections[collectionID] = struct{}{}
+	}
+}
+
+// UnmarkHot undoes MarkHot.
+func (ob *HandoffObserver) UnmarkHot(collectionIDs ...int64) {
+	ob.hotCollectionsLock.Lock()
+	defer ob.hotCollectionsLock.Unlock()
+
+	for _, collectionID := range collectionIDs {
+		delete(ob.hotCollections, collectionID)
+	}
+}
+
+func (ob *HandoffObserver) isHotCollection(collectionID int64) bool {
+	ob.hotCollectionsLock.RLock()
+	defer ob.hotCollectionsLock.RUnlock()
+
+	_, ok := ob.hotCollections[collectionID]
+	return ok
+}
+
+// isPriorityEvent decides whether an event should jump the line within its collection's
+// queue: fake segments (cheap, no actual handoff work) and small segments finish handoff
+// fastest, and hot collections are operator-flagged as latency-sensitive.
+func (ob *HandoffObserver) isPriorityEvent(segment *querypb.SegmentInfo) bool {
+	return segment.GetIsFake() ||
+		segment.GetNumRows() <= Params.QueryCoordCfg.HandoffPrioritySmallSegmentRows ||
+		ob.isHotCollection(segment.GetCollectionID())
+}
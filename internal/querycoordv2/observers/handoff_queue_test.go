@@ -0,0 +1,64 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observers
+
+import (
+	"testing"
+
+	"github.com/milvus-io/milvus/internal/proto/querypb"
+	"github.com/stretchr/testify/assert"
+)
+
+func eventFor(collectionID, segmentID int64) *HandoffEvent {
+	return &HandoffEvent{
+		Segment: &querypb.SegmentInfo{CollectionID: collectionID, SegmentID: segmentID},
+		Status:  HandoffEventStatusReceived,
+	}
+}
+
+func TestHandoffQueueRoundRobinsAcrossCollections(t *testing.T) {
+	q := newHandoffQueue()
+
+	// collection 1 gets two events before collection 2 gets any, but drain should still
+	// interleave by collection instead of starving collection 2.
+	q.push(eventFor(1, 10), false)
+	q.push(eventFor(1, 11), false)
+	q.push(eventFor(2, 20), false)
+
+	drained := q.drain()
+	assert.Len(t, drained, 3)
+	assert.Equal(t, int64(10), drained[0].Segment.GetSegmentID())
+	assert.Equal(t, int64(20), drained[1].Segment.GetSegmentID())
+	assert.Equal(t, int64(11), drained[2].Segment.GetSegmentID())
+}
+
+func TestHandoffQueuePriorityJumpsLine(t *testing.T) {
+	q := newHandoffQueue()
+
+	q.push(eventFor(1, 10), false)
+	q.push(eventFor(1, 11), true) // priority: should overtake 10 within collection 1
+
+	drained := q.drain()
+	assert.Len(t, drained, 2)
+	assert.Equal(t, int64(11), drained[0].Segment.GetSegmentID())
+	assert.Equal(t, int64(10), drained[1].Segment.GetSegmentID())
+}
+
+func TestHandoffQueueDrainEmpty(t *testing.T) {
+	q := newHandoffQueue()
+	assert.Empty(t, q.drain())
+}
@@ -0,0 +1,121 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observers
+
+import (
+	"time"
+
+	"github.com/milvus-io/milvus/internal/log"
+	"go.uber.org/zap"
+)
+
+// HandoffPhase is a state transition in a handoff event's life, emitted on the Subscribe()
+// channel so operators and tests can observe progress without polling internal maps.
+type HandoffPhase string
+
+const (
+	HandoffPhaseReceived  HandoffPhase = "received"
+	HandoffPhaseTriggered HandoffPhase = "triggered"
+	HandoffPhaseReleased  HandoffPhase = "released"
+	HandoffPhaseCleaned   HandoffPhase = "cleaned"
+)
+
+// HandoffLifecycleRecord is one state transition of one segment's handoff.
+type HandoffLifecycleRecord struct {
+	SegmentID    int64
+	CollectionID int64
+	Phase        HandoffPhase
+	Timestamp    time.Time
+	Err          error
+}
+
+// subscriberBufferSize bounds how far behind a subscriber can fall before its records start
+// being dropped instead of blocking the handoff loop.
+const subscriberBufferSize = 256
+
+// Subscribe returns a channel of HandoffLifecycleRecord emitted at each state transition in
+// tryHandoff, tryRelease, and tryClean, along with an unsubscribe func. Sends are non-blocking:
+// a subscriber that can't keep up loses records rather than stalling handoff, and dropped
+// records are counted in handoffSubscriberDropsTotal. Callers that may subscribe more than once
+// over the observer's lifetime (reconnects, periodic test harnesses, ...) must call unsubscribe
+// when done, or the channel and its slot are leaked for the observer's lifetime.
+func (ob *HandoffObserver) Subscribe() (ch <-chan HandoffLifecycleRecord, unsubscribe func()) {
+	c := make(chan HandoffLifecycleRecord, subscriberBufferSize)
+
+	ob.subscribersLock.Lock()
+	ob.subscribers[c] = struct{}{}
+	ob.subscribersLock.Unlock()
+
+	return c, func() {
+		ob.subscribersLock.Lock()
+		defer ob.subscribersLock.Unlock()
+		delete(ob.subscribers, c)
+	}
+}
+
+func (ob *HandoffObserver) emit(record HandoffLifecycleRecord) {
+	ob.subscribersLock.RLock()
+	defer ob.subscribersLock.RUnlock()
+
+	for ch := range ob.subscribers {
+		select {
+		case ch <- record:
+		default:
+			handoffSubscriberDropsTotal.Inc()
+			log.Warn("HandoffObserver: dropped lifecycle record, subscriber too slow",
+				zap.Int64("segmentID", record.SegmentID),
+				zap.String("phase", string(record.Phase)))
+		}
+	}
+}
+
+// markReceived records when a segment's handoff event was first observed, so the eventual
+// cleanEvent can report end-to-end handoff latency.
+func (ob *HandoffObserver) markReceived(segmentID int64) {
+	ob.receivedAtLock.Lock()
+	defer ob.receivedAtLock.Unlock()
+
+	if _, ok := ob.receivedAt[segmentID]; !ok {
+		ob.receivedAt[segmentID] = time.Now()
+	}
+}
+
+// takeReceivedAt returns and forgets the receipt time recorded by markReceived, if any.
+func (ob *HandoffObserver) takeReceivedAt(segmentID int64) (time.Time, bool) {
+	ob.receivedAtLock.Lock()
+	defer ob.receivedAtLock.Unlock()
+
+	t, ok := ob.receivedAt[segmentID]
+	if ok {
+		delete(ob.receivedAt, segmentID)
+	}
+	return t, ok
+}
+
+// updatePendingGaugeLocked recomputes handoffPendingEvents from handoffEvents. Callers must
+// hold ob.handoffEventLock.
+func (ob *HandoffObserver) updatePendingGaugeLocked() {
+	counts := map[int64]float64{}
+	for _, event := range ob.handoffEvents {
+		counts[event.Segment.GetCollectionID()]++
+	}
+
+	handoffPendingEvents.Reset()
+	for collectionID, count := range counts {
+		handoffPendingEvents.WithLabelValues(formatCollectionID(collectionID)).Set(count)
+	}
+}
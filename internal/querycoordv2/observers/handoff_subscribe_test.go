@@ -0,0 +1,60 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestObserverWithSubscribers() *HandoffObserver {
+	return &HandoffObserver{
+		leaderChanged: make(chan bool, 1),
+		subscribers:   map[chan HandoffLifecycleRecord]struct{}{},
+	}
+}
+
+func TestSubscribeReceivesEmittedRecords(t *testing.T) {
+	ob := newTestObserverWithSubscribers()
+
+	ch, unsubscribe := ob.Subscribe()
+	defer unsubscribe()
+
+	ob.emit(HandoffLifecycleRecord{SegmentID: 1, Phase: HandoffPhaseReceived})
+
+	select {
+	case record := <-ch:
+		assert.Equal(t, int64(1), record.SegmentID)
+	case <-time.After(time.Second):
+		t.Fatal("expected a record from the subscription")
+	}
+}
+
+func TestUnsubscribeStopsDeliveryAndFreesSlot(t *testing.T) {
+	ob := newTestObserverWithSubscribers()
+
+	_, unsubscribe := ob.Subscribe()
+	assert.Len(t, ob.subscribers, 1)
+
+	unsubscribe()
+	assert.Len(t, ob.subscribers, 0)
+
+	// emitting after unsubscribe must not panic or block on the now-abandoned channel
+	ob.emit(HandoffLifecycleRecord{SegmentID: 2, Phase: HandoffPhaseReceived})
+}
@@ -0,0 +1,67 @@
+// Licensed to the LF AI & Data foundation under one
+// or more contributor license agreements. See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership. The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License. You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package params
+
+import "time"
+
+// Params is the process-wide QueryCoord parameter table. It is populated from the component's
+// config file/etcd-backed overrides at startup; the defaults below are only used by
+// package-local tests that construct a HandoffObserver without going through that init path.
+var Params = &ParamTable{
+	QueryCoordCfg: queryCoordConfig{
+		AutoHandoff:                      true,
+		CheckHandoffInterval:             1 * time.Second,
+		HandoffEventCompressionCodec:     1, // HandoffCodecGzip, see meta.HandoffCodec
+		HandoffEventCompressionThreshold: 1 << 10,
+		HandoffLeaseTTL:                  10 * time.Second,
+		HandoffTaskRateLimit:             1000,
+		HandoffPrioritySmallSegmentRows:  1024,
+	},
+}
+
+// ParamTable groups the per-component config tables QueryCoord reads from.
+type ParamTable struct {
+	QueryCoordCfg queryCoordConfig
+}
+
+// queryCoordConfig holds QueryCoord's tunables. Only the fields the handoff package depends on
+// are declared here.
+type queryCoordConfig struct {
+	// AutoHandoff controls whether HandoffObserver acts on received handoff events at all.
+	AutoHandoff bool
+	// CheckHandoffInterval is how often schedule() sweeps handoffEvents for stalled transitions.
+	CheckHandoffInterval time.Duration
+
+	// HandoffEventCompressionCodec selects the meta.HandoffCodec used to compress handoff event
+	// values before they're written to etcd (0 = HandoffCodecRaw/disabled).
+	HandoffEventCompressionCodec int
+	// HandoffEventCompressionThreshold is the marshaled size, in bytes, above which a handoff
+	// event value is compressed instead of stored raw.
+	HandoffEventCompressionThreshold int
+
+	// HandoffLeaseTTL is the etcd session TTL HandoffObserver campaigns with for the handoff
+	// leadership. A shorter TTL fails over faster after a leader crash; a longer one tolerates
+	// more GC pause/network jitter before losing leadership spuriously.
+	HandoffLeaseTTL time.Duration
+
+	// HandoffTaskRateLimit caps how many segments per second HandoffObserver registers with the
+	// target manager, so a compaction storm producing a burst of handoff events can't hammer it.
+	HandoffTaskRateLimit float64
+	// HandoffPrioritySmallSegmentRows is the row-count threshold at or below which a segment's handoff
+	// jumps to the front of its collection's queue, since small segments finish handoff fastest.
+	HandoffPrioritySmallSegmentRows int64
+}